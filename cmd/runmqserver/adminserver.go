@@ -0,0 +1,69 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"net/http"
+	"os"
+	"syscall"
+
+	"github.com/ibm-messaging/mq-container/internal/httpadmin"
+)
+
+func adminServerEnabled() bool {
+	e := os.Getenv("MQ_ALPHA_ADMIN_SERVER")
+	return e == "true" || e == "1"
+}
+
+func adminServerAddr() string {
+	if port := os.Getenv("MQ_ADMIN_PORT"); port != "" {
+		return ":" + port
+	}
+	return ":9157"
+}
+
+// startAdminServer starts the HTTP(S) admin control plane provided by
+// internal/httpadmin, wiring its handlers up to this process's queue
+// manager lifecycle functions. The qmgr/stop handler sends this process a
+// SIGTERM rather than calling stopQueueManager directly, so shutdown goes
+// through signalHandler's ordered path exactly as an OS signal does.
+// (Sending terminate on signalControl would not: doMain also receives on that
+// channel and could take the command itself, exiting without stopping the
+// queue manager.)
+func startAdminServer(name string, signalControl chan signalControlCommand, metricsHandler http.Handler) (*http.Server, error) {
+	cfg := httpadmin.Config{
+		Addr:        adminServerAddr(),
+		TLSCertFile: os.Getenv("MQ_ADMIN_TLS_CERT"),
+		TLSKeyFile:  os.Getenv("MQ_ADMIN_TLS_KEY"),
+		TLSCAFile:   os.Getenv("MQ_ADMIN_TLS_CA"),
+		Metrics:     metricsHandler,
+		Ready: func() (bool, error) {
+			return isReady(name)
+		},
+		Live: func() (bool, error) {
+			return isLive(name)
+		},
+		Stop: func() error {
+			return syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		},
+		ReloadMQSC: configureQueueManager,
+		Status: func() (string, error) {
+			return queueManagerStatus(name)
+		},
+	}
+	return httpadmin.Start(cfg)
+}
@@ -0,0 +1,38 @@
+/*
+© Copyright IBM Corporation 2017, 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import "os"
+
+// checkLicense reports whether the MQ license has been accepted, via the
+// LICENSE=accept environment variable.
+func checkLicense() (bool, error) {
+	return os.Getenv("LICENSE") == "accept", nil
+}
+
+// logConfig logs the environment variables which affect runmqserver's
+// behaviour, for diagnostic purposes.
+func logConfig() {
+	logDebugf("MQ_QMGR_NAME=%v", os.Getenv("MQ_QMGR_NAME"))
+	logDebugf("LICENSE=%v", os.Getenv("LICENSE"))
+}
+
+// createVolume ensures path exists, so it can be used as a mount point even
+// if nothing has been mounted there.
+func createVolume(path string) error {
+	return os.MkdirAll(path, 0755)
+}
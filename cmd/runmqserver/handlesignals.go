@@ -0,0 +1,97 @@
+/*
+© Copyright IBM Corporation 2017, 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// signalControlCommand is sent on the channel returned by signalHandler,
+// both by doMain (to tell the handler goroutine to start/perform zombie
+// reaping) and by the handler goroutine itself (to tell doMain that the
+// queue manager has been stopped and it is safe to exit).
+type signalControlCommand int
+
+const (
+	startReaping signalControlCommand = iota
+	reapNow
+	// terminate is sent back to doMain once the queue manager has been
+	// stopped, in response to an OS signal (including the SIGTERM that the
+	// httpadmin qmgr/stop handler sends this process).
+	terminate
+)
+
+// signalHandler starts a goroutine which waits for either SIGTERM/SIGINT, or
+// a signalControlCommand sent by doMain, and returns the channel used to
+// communicate with it. On SIGTERM/SIGINT, or on receiving terminate, it
+// stops the queue manager in an orderly fashion and signals doMain that it
+// is safe to exit.
+func signalHandler(name string) chan signalControlCommand {
+	signalControl := make(chan signalControlCommand)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	reap := false
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				log.Printf("Received signal: %v", sig)
+				shutdownQueueManager(name, signalControl)
+				return
+			case cmd := <-signalControl:
+				switch cmd {
+				case startReaping:
+					reap = true
+				case reapNow:
+					if reap {
+						reapZombies()
+					}
+				case terminate:
+					shutdownQueueManager(name, signalControl)
+					return
+				}
+			}
+		}
+	}()
+	return signalControl
+}
+
+// shutdownQueueManager stops the queue manager and signals doMain (via
+// signalControl) that it has done so and that it is safe to exit.
+func shutdownQueueManager(name string, signalControl chan signalControlCommand) {
+	err := stopQueueManager(name)
+	if err != nil {
+		log.Println(err)
+	}
+	signalControl <- terminate
+}
+
+// reapZombies reaps any zombie child processes (e.g. left over from crtmqm
+// or strmqm), so that this process can safely act as PID 1 in a container.
+func reapZombies() {
+	for {
+		var status syscall.WaitStatus
+		pid, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+		if err != nil || pid <= 0 {
+			return
+		}
+	}
+}
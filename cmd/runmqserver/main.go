@@ -18,9 +18,10 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -29,7 +30,11 @@ import (
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/ibm-messaging/mq-container/internal/backup"
 	"github.com/ibm-messaging/mq-container/internal/command"
+	"github.com/ibm-messaging/mq-container/internal/logmirror"
+	"github.com/ibm-messaging/mq-container/internal/metrics"
+	"github.com/ibm-messaging/mq-container/internal/mqconfig"
 	"github.com/ibm-messaging/mq-container/internal/name"
 )
 
@@ -130,6 +135,14 @@ func configureQueueManager() error {
 			log.Printf("Output for \"runmqsc\" with %v:\n\t%v", abs, strings.Replace(string(out), "\n", "\n\t", -1))
 		}
 	}
+
+	// Also apply any declarative YAML/JSON configuration (queues.yaml,
+	// channels.yaml, auth.yaml, etc.), rendered to idempotent MQSC.
+	err = mqconfig.Apply(configDir)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
 	return nil
 }
 
@@ -205,6 +218,9 @@ func doMain() error {
 
 	// Start signal handler
 	signalControl := signalHandler(name)
+	// Start the backup/restore signal handler (SIGUSR1/SIGUSR2), alongside
+	// the main signal handler above
+	backup.RegisterSignalHandlers(name)
 
 	logConfig()
 	err = createVolume("/mnt/mqm")
@@ -216,30 +232,23 @@ func doMain() error {
 	if err != nil {
 		return err
 	}
+	err = createQueueManager(name)
+	if err != nil {
+		return err
+	}
+	// Started only now, since the errors directory this watches is created
+	// by crtmqm (createQueueManager above), not by createDirStructure.
 	var mirrorLifecycle chan bool
 	if mirrorLogs() {
-		f := "/var/mqm/qmgrs/" + name + "/errors/AMQERR01"
-		if jsonLogs() {
-			f = f + ".json"
-			mirrorLifecycle, err = mirrorLog(f, func(msg string) {
-				// Print the message straight to stdout
-				fmt.Println(msg)
-			})
-		} else {
-			f = f + ".LOG"
-			mirrorLifecycle, err = mirrorLog(f, func(msg string) {
-				// Log the message, so we get a timestamp etc.
-				log.Println(msg)
-			})
+		sink, err := logmirror.NewSink(os.Getenv("MQ_LOGMIRROR_SINK"), os.Getenv("MQ_LOGMIRROR_WEBHOOK_URL"), jsonLogs())
+		if err != nil {
+			return err
 		}
+		mirrorLifecycle, err = logmirror.Start("/var/mqm/qmgrs/"+name+"/errors", sink)
 		if err != nil {
 			return err
 		}
 	}
-	err = createQueueManager(name)
-	if err != nil {
-		return err
-	}
 	err = updateCommandLevel()
 	if err != nil {
 		return err
@@ -249,6 +258,27 @@ func doMain() error {
 		return err
 	}
 	configureQueueManager()
+	var metricsHandler http.Handler
+	var metricsStop chan struct{}
+	if metricsEnabled() {
+		metricsStop = make(chan struct{})
+		metricsHandler, err = metrics.Start(name, metricsStop)
+		if err != nil {
+			return err
+		}
+	}
+	var adminServer *http.Server
+	if adminServerEnabled() {
+		adminServer, err = startAdminServer(name, signalControl, metricsHandler)
+		if err != nil {
+			return err
+		}
+	} else if metricsHandler != nil {
+		adminServer, err = startMetricsOnlyServer(metricsHandler)
+		if err != nil {
+			return err
+		}
+	}
 	// Start reaping zombies from now on.
 	// Start this here, so that we don't reap any sub-processes created
 	// by this process (e.g. for crtmqm or strmqm)
@@ -257,6 +287,12 @@ func doMain() error {
 	signalControl <- reapNow
 	// Wait for terminate signal
 	<-signalControl
+	if adminServer != nil {
+		adminServer.Shutdown(context.Background())
+	}
+	if metricsStop != nil {
+		close(metricsStop)
+	}
 	if mirrorLogs() {
 		// Tell the mirroring goroutine to shutdown
 		mirrorLifecycle <- true
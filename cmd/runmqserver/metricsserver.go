@@ -0,0 +1,42 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"net/http"
+	"os"
+)
+
+func metricsEnabled() bool {
+	e := os.Getenv("MQ_ENABLE_METRICS")
+	return e == "true" || e == "1"
+}
+
+// startMetricsOnlyServer starts a bare HTTP server serving only /metrics, for
+// the case where MQ_ENABLE_METRICS is set but the wider admin control plane
+// (MQ_ALPHA_ADMIN_SERVER) is not. When the admin server is enabled, the
+// metrics handler is mounted on it directly instead (see startAdminServer).
+func startMetricsOnlyServer(handler http.Handler) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+	server := &http.Server{
+		Addr:    adminServerAddr(),
+		Handler: mux,
+	}
+	go server.ListenAndServe()
+	return server, nil
+}
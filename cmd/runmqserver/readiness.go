@@ -0,0 +1,68 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+
+	"github.com/ibm-messaging/mq-container/internal/command"
+)
+
+// queueManagerStatus runs dspmq and returns the STATUS field reported for
+// name, e.g. "Running", "Starting", "Ended normally".
+func queueManagerStatus(name string) (string, error) {
+	out, _, err := command.Run("dspmq", "-n", "-m", name)
+	if err != nil {
+		return "", err
+	}
+	return parseDspmqStatus(string(out)), nil
+}
+
+// parseDspmqStatus extracts the STATUS value from a line of dspmq -n output,
+// e.g. "QMNAME(QM1) STATUS(Running)".
+func parseDspmqStatus(out string) string {
+	const key = "STATUS("
+	i := strings.Index(out, key)
+	if i == -1 {
+		return ""
+	}
+	rest := out[i+len(key):]
+	j := strings.Index(rest, ")")
+	if j == -1 {
+		return ""
+	}
+	return rest[:j]
+}
+
+// isReady reports whether the queue manager is ready to accept connections.
+func isReady(name string) (bool, error) {
+	status, err := queueManagerStatus(name)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(status, "Running"), nil
+}
+
+// isLive reports whether the queue manager process is alive at all. This is
+// a weaker condition than isReady, intended for a Kubernetes liveness probe.
+func isLive(name string) (bool, error) {
+	status, err := queueManagerStatus(name)
+	if err != nil {
+		return false, err
+	}
+	return !strings.HasPrefix(strings.ToLower(status), "ended"), nil
+}
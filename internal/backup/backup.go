@@ -0,0 +1,183 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/ibm-messaging/mq-container/internal/command"
+)
+
+// dataDirs are the directories backed up for a given queue manager.
+func dataDirs(name string) []string {
+	return []string{
+		filepath.Join("/var/mqm/qmgrs", name),
+		filepath.Join("/var/mqm/log", name),
+	}
+}
+
+// Backup quiesces the queue manager, then streams a tar+gzip archive of its
+// data and log directories, preceded by a JSON manifest, to dest (a local
+// path, an s3://bucket/key URL, or "-"/"" for stdout).
+func Backup(name string, dest string) (err error) {
+	log.Printf("Starting backup of queue manager %v to %v", name, dest)
+	out, rc, err := command.Run("endmqm", "-w", "-q", name)
+	if err != nil {
+		return fmt.Errorf("backup: quiescing queue manager (rc=%v): %v: %v", rc, err, string(out))
+	}
+	// Always try to bring the queue manager back up again, even if the
+	// archive fails partway through, so a backup never leaves the
+	// container permanently out of service.
+	defer restartQueueManager(name)
+
+	w, err := openDestinationWriter(dest)
+	if err != nil {
+		return fmt.Errorf("backup: opening destination %v: %v", dest, err)
+	}
+	// Closing w is what actually waits for an S3 upload to finish and
+	// surfaces its result, so its error must propagate rather than be
+	// discarded: a failed upload must not be reported as a successful
+	// backup. Close in reverse order of creation (tw, then gz, then w) so
+	// each writer is flushed before the one underneath it is finalized,
+	// and don't let a close error mask an earlier, more specific failure.
+	defer func() {
+		if closeErr := w.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("backup: closing destination %v: %v", dest, closeErr)
+		}
+	}()
+
+	gz := gzip.NewWriter(w)
+	defer func() {
+		if closeErr := gz.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("backup: closing archive: %v", closeErr)
+		}
+	}()
+	tw := tar.NewWriter(gz)
+	defer func() {
+		if closeErr := tw.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("backup: closing archive: %v", closeErr)
+		}
+	}()
+
+	m := manifest{
+		QueueManager: name,
+		MQVersion:    mqVersion(),
+		CmdLevel:     os.Getenv("MQ_CMDLEVEL"),
+		CreatedAt:    time.Now(),
+	}
+	if err := writeManifest(tw, m); err != nil {
+		return fmt.Errorf("backup: writing manifest: %v", err)
+	}
+
+	for _, dir := range dataDirs(name) {
+		if err := addDirToTar(tw, dir); err != nil {
+			return fmt.Errorf("backup: archiving %v: %v", dir, err)
+		}
+	}
+	log.Printf("Completed backup of queue manager %v", name)
+	return nil
+}
+
+// restartQueueManager brings name back up after Backup has quiesced it,
+// logging rather than failing on error since it runs as a deferred
+// best-effort cleanup.
+func restartQueueManager(name string) {
+	out, rc, err := command.Run("strmqm", name)
+	if err != nil {
+		log.Printf("backup: error %v restarting queue manager %v: %v", rc, name, string(out))
+		return
+	}
+	log.Printf("Restarted queue manager %v after backup", name)
+}
+
+// writeManifest writes m as manifestName, the first entry in the archive.
+func writeManifest(tw *tar.Writer, m manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Name: manifestName,
+		Mode: 0644,
+		Size: int64(len(b)),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = tw.Write(b)
+	return err
+}
+
+// addDirToTar walks dir, adding every regular file and directory under it to
+// tw with a path relative to /var/mqm (dir's grandparent), e.g.
+// "qmgrs/QM1/...", which is the root Restore extracts into.
+func addDirToTar(tw *tar.Writer, dir string) error {
+	base := filepath.Dir(filepath.Dir(dir))
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// mqVersion returns the installed MQ product version, read via dspmqver,
+// falling back to "unknown" if it can't be determined.
+func mqVersion() string {
+	out, _, err := command.Run("dspmqver", "-b", "-f", "2")
+	if err != nil {
+		return "unknown"
+	}
+	return trimTrailingNewline(string(out))
+}
+
+func trimTrailingNewline(s string) string {
+	for len(s) > 0 && (s[len(s)-1] == '\n' || s[len(s)-1] == '\r') {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,130 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// openDestinationWriter returns a writer for dest, which may be "-" (stdout),
+// an s3://bucket/key URL, or a local file path.
+func openDestinationWriter(dest string) (io.WriteCloser, error) {
+	switch {
+	case dest == "" || dest == "-" || dest == "stdout":
+		return writeNopCloser{os.Stdout}, nil
+	case strings.HasPrefix(dest, "s3://"):
+		return newS3Writer(dest)
+	default:
+		return os.Create(dest)
+	}
+}
+
+// openSourceReader returns a reader for src, the counterpart to
+// openDestinationWriter, used when restoring.
+func openSourceReader(src string) (io.ReadCloser, error) {
+	switch {
+	case src == "" || src == "-" || src == "stdin":
+		return ioutil.NopCloser(os.Stdin), nil
+	case strings.HasPrefix(src, "s3://"):
+		return newS3Reader(src)
+	default:
+		return os.Open(src)
+	}
+}
+
+// writeNopCloser adapts a writer that shouldn't be closed (stdout) to the
+// io.WriteCloser interface.
+type writeNopCloser struct {
+	io.Writer
+}
+
+func (writeNopCloser) Close() error { return nil }
+
+// s3URL splits an s3://bucket/key URL into its bucket and key parts.
+func s3URL(url string) (bucket, key string) {
+	trimmed := strings.TrimPrefix(url, "s3://")
+	parts := strings.SplitN(trimmed, "/", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return parts[0], ""
+}
+
+// newS3Writer streams a backup archive to an S3-compatible destination as it
+// is written, using an in-process pipe so the tar writer never needs to
+// buffer the whole archive on disk.
+func newS3Writer(dest string) (io.WriteCloser, error) {
+	bucket, key := s3URL(dest)
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	uploader := s3manager.NewUploader(sess)
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, err := uploader.Upload(&s3manager.UploadInput{
+			Bucket: &bucket,
+			Key:    &key,
+			Body:   pr,
+		})
+		done <- err
+	}()
+	return &s3PipeWriter{pw: pw, done: done}, nil
+}
+
+type s3PipeWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (w *s3PipeWriter) Write(p []byte) (int, error) { return w.pw.Write(p) }
+
+func (w *s3PipeWriter) Close() error {
+	w.pw.Close()
+	return <-w.done
+}
+
+// newS3Reader downloads an S3-compatible archive into memory and returns a
+// reader over it. Restores are expected to happen once at container
+// startup, so buffering the whole archive keeps the code simple.
+func newS3Reader(src string) (io.ReadCloser, error) {
+	bucket, key := s3URL(src)
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	downloader := s3manager.NewDownloader(sess)
+	buf := &aws.WriteAtBuffer{}
+	_, err = downloader.Download(buf, &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(buf.Bytes())), nil
+}
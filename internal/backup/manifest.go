@@ -0,0 +1,36 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup provides a signal-driven backup and restore workflow for a
+// queue manager's data and log directories, so cluster operators have a
+// supported, in-container disaster-recovery path instead of ad-hoc
+// `kubectl cp`.
+package backup
+
+import "time"
+
+// manifestName is the file recorded alongside the queue manager's data in
+// every backup archive.
+const manifestName = "manifest.json"
+
+// manifest records enough information to sanity-check a backup before it is
+// restored onto a (possibly different) queue manager image.
+type manifest struct {
+	QueueManager string    `json:"queueManager"`
+	MQVersion    string    `json:"mqVersion"`
+	CmdLevel     string    `json:"cmdLevel"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
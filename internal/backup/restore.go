@@ -0,0 +1,123 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Restore extracts a tar+gzip archive previously written by Backup, built
+// from src (a local path, an s3://bucket/key URL, or "-"/"" for stdin), back
+// into /var/mqm. It refuses to run if the queue manager's data directory
+// already exists, unless force is true.
+func Restore(name string, src string, force bool) error {
+	dataDir := filepath.Join("/var/mqm/qmgrs", name)
+	if _, err := os.Stat(dataDir); err == nil && !force {
+		return fmt.Errorf("restore: queue manager %v already exists; set MQ_BACKUP_FORCE=true to overwrite", name)
+	}
+
+	log.Printf("Starting restore of queue manager %v from %v", name, src)
+	r, err := openSourceReader(src)
+	if err != nil {
+		return fmt.Errorf("restore: opening source %v: %v", src, err)
+	}
+	defer r.Close()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("restore: reading archive: %v", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("restore: reading archive: %v", err)
+		}
+		if hdr.Name == manifestName {
+			if err := checkManifest(tr, name); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := extractEntry(tr, hdr, "/var/mqm"); err != nil {
+			return fmt.Errorf("restore: extracting %v: %v", hdr.Name, err)
+		}
+	}
+	log.Printf("Completed restore of queue manager %v", name)
+	return nil
+}
+
+// checkManifest logs the manifest recorded in the archive and warns if it
+// was taken for a different queue manager name; it does not otherwise block
+// the restore, since renaming a queue manager on restore is a valid use.
+func checkManifest(r io.Reader, name string) error {
+	var m manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return fmt.Errorf("restore: decoding manifest: %v", err)
+	}
+	log.Printf("Restoring backup of %v taken at %v (MQ version %v, CMDLEVEL %v)",
+		m.QueueManager, m.CreatedAt, m.MQVersion, m.CmdLevel)
+	if m.QueueManager != name {
+		log.Printf("Warning: backup was taken for queue manager %v, restoring as %v", m.QueueManager, name)
+	}
+	return nil
+}
+
+// extractEntry writes a single tar entry (relative to root) to disk. Entries
+// whose path would resolve outside root are rejected, so a crafted archive
+// (e.g. fetched from S3 or piped over stdin) can't write outside the
+// intended destination directory.
+func extractEntry(r io.Reader, hdr *tar.Header, root string) error {
+	target := filepath.Join(root, hdr.Name)
+	if target != root && !strings.HasPrefix(target, root+string(filepath.Separator)) {
+		return fmt.Errorf("entry %v escapes %v", hdr.Name, root)
+	}
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, os.FileMode(hdr.Mode))
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(hdr.Mode))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(f, r)
+		return err
+	default:
+		// Symlinks and other special entries aren't expected in a queue
+		// manager's data/log directories; skip anything unrecognised
+		// rather than failing the whole restore.
+		return nil
+	}
+}
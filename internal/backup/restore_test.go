@@ -0,0 +1,59 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractEntryRejectsPathTraversal(t *testing.T) {
+	root := t.TempDir()
+	hdr := &tar.Header{
+		Name:     "../../etc/passwd",
+		Typeflag: tar.TypeReg,
+		Mode:     0644,
+		Size:     0,
+	}
+	err := extractEntry(strings.NewReader(""), hdr, root)
+	if err == nil {
+		t.Fatal("extractEntry: expected an error for a path-traversal entry, got nil")
+	}
+}
+
+func TestExtractEntryWritesWithinRoot(t *testing.T) {
+	root := t.TempDir()
+	hdr := &tar.Header{
+		Name:     "qmgrs/QM1/qm.ini",
+		Typeflag: tar.TypeReg,
+		Mode:     0644,
+		Size:     int64(len("content")),
+	}
+	if err := extractEntry(strings.NewReader("content"), hdr, root); err != nil {
+		t.Fatalf("extractEntry: unexpected error: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(root, "qmgrs/QM1/qm.ini"))
+	if err != nil {
+		t.Fatalf("reading extracted file: %v", err)
+	}
+	if string(b) != "content" {
+		t.Errorf("extracted content = %q, want %q", string(b), "content")
+	}
+}
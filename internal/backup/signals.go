@@ -0,0 +1,50 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RegisterSignalHandlers starts a goroutine which triggers Backup on
+// SIGUSR1 and Restore on SIGUSR2, alongside runmqserver's existing
+// SIGTERM/SIGINT handling. The backup destination is read from
+// MQ_BACKUP_DEST, and MQ_BACKUP_FORCE controls whether a restore is allowed
+// to overwrite an existing queue manager.
+func RegisterSignalHandlers(name string) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for sig := range signals {
+			switch sig {
+			case syscall.SIGUSR1:
+				if err := Backup(name, os.Getenv("MQ_BACKUP_DEST")); err != nil {
+					log.Println(err)
+				}
+			case syscall.SIGUSR2:
+				force := os.Getenv("MQ_BACKUP_FORCE") == "true" || os.Getenv("MQ_BACKUP_FORCE") == "1"
+				if err := Restore(name, os.Getenv("MQ_BACKUP_DEST"), force); err != nil {
+					log.Println(err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,50 @@
+/*
+© Copyright IBM Corporation 2017, 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package command runs MQ administrative commands (crtmqm, strmqm, endmqm,
+// etc.), abstracted behind a pluggable MQRuntime so that the mechanism used
+// to run them can be swapped out (see runtime.go).
+package command
+
+import (
+	"bytes"
+	"os/exec"
+)
+
+// Run executes name with the given arguments, using the currently selected
+// MQRuntime (see SelectRuntime / the MQ_RUNTIME environment variable). It
+// returns the combined stdout/stderr output, the process exit code, and an
+// error if the command could not be run or exited non-zero.
+func Run(name string, arg ...string) ([]byte, int, error) {
+	return active.Run(name, arg...)
+}
+
+// runBinary runs name as a real subprocess. This is the behaviour of the
+// default binaryRuntime.
+func runBinary(name string, arg ...string) ([]byte, int, error) {
+	cmd := exec.Command(name, arg...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	rc := 0
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok {
+			rc = exitError.ExitCode()
+		}
+	}
+	return out.Bytes(), rc, err
+}
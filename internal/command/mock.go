@@ -0,0 +1,62 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// mockRuntime is an MQRuntime that records the commands it is asked to run
+// and returns success for all of them, without touching the filesystem or
+// spawning any subprocess. It is selected with MQ_RUNTIME=mock, and is
+// intended for integration testing of runmqserver's control flow without a
+// real MQ installation.
+type mockRuntime struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func newMockRuntime() *mockRuntime {
+	return &mockRuntime{}
+}
+
+func (m *mockRuntime) Run(name string, arg ...string) ([]byte, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	call := fmt.Sprintf("%v %v", name, arg)
+	m.calls = append(m.calls, call)
+	log.Debugf("mock runtime: %v", call)
+	return []byte{}, 0, nil
+}
+
+// Calls returns the commands run so far, in order, formatted as
+// "name [arg1 arg2 ...]". It is exported so integration tests running with
+// MQ_RUNTIME=mock can assert on what runmqserver attempted to run.
+func Calls() []string {
+	m, ok := active.(*mockRuntime)
+	if !ok {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]string, len(m.calls))
+	copy(out, m.calls)
+	return out
+}
@@ -0,0 +1,89 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MQRuntime abstracts the mechanism used to run MQ administrative commands
+// such as crtmqm, strmqm, endmqm and dspmq. Run takes no standard input, so
+// runmqsc (which reads its script from standard input) is not run through an
+// MQRuntime; its callers start it directly. The default runtime runs the
+// named binary as a local subprocess; other runtimes can be registered and
+// selected by name via the MQ_RUNTIME environment variable, e.g. to run
+// against a mock for integration tests, or to drive an existing queue
+// manager remotely over PCF instead of spawning local binaries.
+type MQRuntime interface {
+	// Run executes name with the given arguments and returns its combined
+	// stdout/stderr output, exit code, and any error encountered.
+	Run(name string, arg ...string) (out []byte, rc int, err error)
+}
+
+// binaryRuntime is the default MQRuntime. It runs the named binary directly
+// as a subprocess, exactly as runmqserver has always done.
+type binaryRuntime struct{}
+
+func (binaryRuntime) Run(name string, arg ...string) ([]byte, int, error) {
+	return runBinary(name, arg...)
+}
+
+// runtimes holds the built-in named runtimes, selectable via MQ_RUNTIME.
+// Additional runtimes can be added with RegisterRuntime.
+var runtimes = map[string]MQRuntime{
+	"binary": binaryRuntime{},
+	"mock":   newMockRuntime(),
+}
+
+// active is the MQRuntime used by Run. It defaults to binaryRuntime and is
+// set from MQ_RUNTIME at package initialisation, or later by RegisterRuntime
+// when a runtime is registered under the name MQ_RUNTIME holds.
+var active MQRuntime = binaryRuntime{}
+
+func init() {
+	name, ok := os.LookupEnv("MQ_RUNTIME")
+	if !ok || name == "" {
+		return
+	}
+	SelectRuntime(name)
+}
+
+// RegisterRuntime makes rt selectable by name via MQ_RUNTIME. It is intended
+// to be called from an init() function by packages providing additional
+// runtimes (e.g. a remote-admin runtime that drives a queue manager over PCF).
+func RegisterRuntime(name string, rt MQRuntime) {
+	runtimes[name] = rt
+	// This package's init has already run by the time an importing package's
+	// init calls this, so MQ_RUNTIME is checked again here.
+	if os.Getenv("MQ_RUNTIME") == name {
+		SelectRuntime(name)
+	}
+}
+
+// SelectRuntime sets the runtime used by Run to the one registered under
+// name, logging and falling back to the binary runtime if name is unknown.
+func SelectRuntime(name string) {
+	rt, ok := runtimes[name]
+	if !ok {
+		log.Printf("Unknown MQ_RUNTIME %q; using binary runtime", name)
+		return
+	}
+	log.Printf("Using MQ_RUNTIME %q", name)
+	active = rt
+}
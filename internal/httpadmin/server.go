@@ -0,0 +1,179 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httpadmin exposes an HTTP(S) control plane for runmqserver:
+// readiness/liveness probes, a Prometheus metrics endpoint, and a small set
+// of runtime queue manager operations (stop, reload configuration, status).
+// This gives Kubernetes probes and operators a proper control plane, rather
+// than being limited to exec-based checks.
+package httpadmin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var errInvalidCA = errors.New("unable to parse CA certificate")
+
+// Config describes how to start the admin server, and the callbacks it
+// invokes to perform each operation. Ready, Live, Stop, ReloadMQSC and
+// Status may be left nil, in which case the corresponding endpoint responds
+// with 501 Not Implemented (200 OK for the probes); Metrics may be left nil
+// to omit the /metrics endpoint entirely.
+type Config struct {
+	// Addr is the address to listen on, e.g. ":9157".
+	Addr string
+	// TLSCertFile, TLSKeyFile and TLSCAFile, if all set, enable mTLS using
+	// the same certificates mounted into the container for MQ itself.
+	TLSCertFile string
+	TLSKeyFile  string
+	TLSCAFile   string
+	// Ready reports whether the queue manager is ready to accept connections.
+	Ready func() (bool, error)
+	// Live reports whether the queue manager process is alive.
+	Live func() (bool, error)
+	// Stop requests an orderly shutdown of the queue manager. It should
+	// route through the process's existing signal handling, so that
+	// shutdown remains ordered rather than calling endmqm directly.
+	Stop func() error
+	// ReloadMQSC re-applies the MQSC/declarative configuration under /etc/mqm.
+	ReloadMQSC func() error
+	// Status returns a short queue manager status string, e.g. "Running".
+	Status func() (string, error)
+	// Metrics, if set, serves the /metrics endpoint.
+	Metrics http.Handler
+}
+
+// Start builds the admin HTTP(S) server described by cfg and starts serving
+// in a background goroutine. It returns the *http.Server so the caller can
+// Shutdown it during process termination.
+func Start(cfg Config) (*http.Server, error) {
+	tlsConfig, err := buildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ready", handleProbe(cfg.Ready))
+	mux.HandleFunc("/live", handleProbe(cfg.Live))
+	mux.HandleFunc("/qmgr/stop", handleAction(cfg.Stop))
+	mux.HandleFunc("/qmgr/reload-mqsc", handleAction(cfg.ReloadMQSC))
+	mux.HandleFunc("/qmgr/status", handleStatus(cfg.Status))
+	if cfg.Metrics != nil {
+		mux.Handle("/metrics", cfg.Metrics)
+	}
+
+	server := &http.Server{
+		Addr:      cfg.Addr,
+		Handler:   mux,
+		TLSConfig: tlsConfig,
+	}
+
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			log.Printf("Starting admin server on %v (mTLS)", cfg.Addr)
+			err = server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			log.Printf("Starting admin server on %v", cfg.Addr)
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Printf("Admin server stopped: %v", err)
+		}
+	}()
+	return server, nil
+}
+
+// buildTLSConfig returns a *tls.Config requiring and verifying client
+// certificates against TLSCAFile, or nil if mTLS is not configured.
+func buildTLSConfig(cfg Config) (*tls.Config, error) {
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" || cfg.TLSCAFile == "" {
+		return nil, nil
+	}
+	caCert, err := ioutil.ReadFile(cfg.TLSCAFile)
+	if err != nil {
+		return nil, err
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caCert) {
+		return nil, errInvalidCA
+	}
+	return &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  caPool,
+	}, nil
+}
+
+func handleProbe(check func() (bool, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if check == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		ok, err := check()
+		if err != nil || !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			if err != nil {
+				w.Write([]byte(err.Error()))
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func handleAction(action func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if action == nil {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		if err := action(); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func handleStatus(status func() (string, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if status == nil {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		s, err := status()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": s})
+	}
+}
@@ -0,0 +1,44 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logmirror follows the MQ error logs (AMQERR01/02/03, and their
+// .json equivalents) under a queue manager's errors directory, surviving
+// MQ's own log rotation, and additionally watches for new FDC
+// (First Failure Data Capture) files, emitting a synthesized event for each
+// so diagnostic information isn't limited to whatever happens to be in
+// AMQERR01 at the time.
+package logmirror
+
+import "time"
+
+// EventType distinguishes a plain error log line from an FDC notification.
+type EventType string
+
+const (
+	// EventError is a line read from an AMQERR0{1,2,3} log.
+	EventError EventType = "error"
+	// EventFDC is emitted when a new .FDC file appears.
+	EventFDC EventType = "fdc"
+)
+
+// Event is a single unit of diagnostic output, ready to be handed to a Sink.
+type Event struct {
+	Time    time.Time  `json:"time"`
+	Source  string     `json:"source"` // the file the event came from
+	Type    EventType  `json:"type"`
+	Message string     `json:"message"`
+	FDC     *FDCHeader `json:"fdc,omitempty"`
+}
@@ -0,0 +1,67 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logmirror
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// FDCHeader holds the handful of fields from an FDC file's header that are
+// useful at a glance, plus a pointer to the full file for deeper analysis.
+type FDCHeader struct {
+	ProbeID   string `json:"probeId"`
+	Component string `json:"component"`
+	Severity  string `json:"severity"`
+	Path      string `json:"path"`
+}
+
+// parseFDCHeader reads just the header block of an FDC file (a small,
+// fixed-format section of "Label : Value" lines at the top of the file) and
+// returns the fields of interest, without reading the whole (often large)
+// file into memory.
+func parseFDCHeader(path string) (*FDCHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	header := &FDCHeader{Path: path}
+	scanner := bufio.NewScanner(f)
+	lines := 0
+	for scanner.Scan() && lines < 200 {
+		lines++
+		line := scanner.Text()
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		label := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		switch label {
+		case "Probe Id", "Probe Type":
+			header.ProbeID = value
+		case "Component":
+			header.Component = value
+		case "Probe Severity", "Severity":
+			header.Severity = value
+		}
+	}
+	return header, scanner.Err()
+}
@@ -0,0 +1,112 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logmirror
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Sink is where mirrored log events are delivered.
+type Sink interface {
+	Write(e Event) error
+}
+
+// stdoutSink writes events to the process's own output. In plain-text mode
+// each message goes through logrus, so it gets the same timestamp/formatting
+// treatment as the rest of runmqserver's logging; in JSON mode (json set,
+// e.g. from MQ_ALPHA_JSON_LOGS) each Event is marshalled and printed straight
+// to stdout instead.
+type stdoutSink struct {
+	json bool
+}
+
+func (s stdoutSink) Write(e Event) error {
+	if s.json {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+	log.Println(e.Message)
+	return nil
+}
+
+// syslogSink forwards events to the local syslog daemon.
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+func newSyslogSink() (*syslogSink, error) {
+	w, err := syslog.New(syslog.LOG_ERR|syslog.LOG_USER, "runmqserver")
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{writer: w}, nil
+}
+
+func (s *syslogSink) Write(e Event) error {
+	return s.writer.Err(e.Message)
+}
+
+// webhookSink POSTs each event as JSON to a configured URL.
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookSink(url string) *webhookSink {
+	return &webhookSink{url: url, client: &http.Client{}}
+}
+
+func (s *webhookSink) Write(e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// NewSink builds the Sink selected by name ("stdout", "syslog" or
+// "webhook"). webhookURL is only used when name is "webhook". jsonOutput
+// controls whether the stdout sink emits structured JSON or plain text.
+func NewSink(name string, webhookURL string, jsonOutput bool) (Sink, error) {
+	switch name {
+	case "", "stdout":
+		return stdoutSink{json: jsonOutput}, nil
+	case "syslog":
+		return newSyslogSink()
+	case "webhook":
+		if webhookURL == "" {
+			return nil, fmt.Errorf("logmirror: MQ_LOGMIRROR_WEBHOOK_URL must be set when MQ_LOGMIRROR_SINK=webhook")
+		}
+		return newWebhookSink(webhookURL), nil
+	default:
+		return nil, fmt.Errorf("logmirror: unknown sink %q", name)
+	}
+}
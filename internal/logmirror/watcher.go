@@ -0,0 +1,225 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logmirror
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+// trackedLogs are the error log base names followed, in both their .LOG and
+// .json forms.
+var trackedLogs = []string{"AMQERR01", "AMQERR02", "AMQERR03"}
+
+// Watcher follows the error logs and FDC files under a queue manager's
+// errors directory and forwards events to a Sink.
+type Watcher struct {
+	dir     string
+	sink    Sink
+	offsets map[string]int64
+	seenFDC map[string]bool
+}
+
+// Start begins watching dir and returns a lifecycle channel: send true on
+// it to request a clean shutdown, then receive from it once to know the
+// watcher goroutine has exited. This matches the lifecycle protocol
+// runmqserver already uses for background goroutines it needs to drain
+// before the process exits.
+func Start(dir string, sink Sink) (chan bool, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	w := &Watcher{
+		dir:     dir,
+		sink:    sink,
+		offsets: map[string]int64{},
+		seenFDC: map[string]bool{},
+	}
+	// Start each log from its current end, so only lines written from now
+	// on are mirrored, not the whole pre-existing file.
+	for _, base := range trackedLogs {
+		for _, suffix := range []string{".LOG", ".json"} {
+			path := filepath.Join(dir, base+suffix)
+			if info, err := os.Stat(path); err == nil {
+				w.offsets[path] = info.Size()
+			}
+		}
+	}
+	w.scanForFDCs(true)
+
+	lifecycle := make(chan bool)
+	go w.run(watcher, lifecycle)
+	return lifecycle, nil
+}
+
+func (w *Watcher) run(watcher *fsnotify.Watcher, lifecycle chan bool) {
+	defer watcher.Close()
+	for {
+		select {
+		case <-lifecycle:
+			lifecycle <- true
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				lifecycle <- true
+				return
+			}
+			w.handleEvent(event)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				lifecycle <- true
+				return
+			}
+			log.Printf("logmirror: watch error: %v", err)
+		}
+	}
+}
+
+func (w *Watcher) handleEvent(event fsnotify.Event) {
+	base := filepath.Base(event.Name)
+	if strings.HasSuffix(base, ".FDC") {
+		if event.Op&(fsnotify.Create|fsnotify.Write) != 0 {
+			w.scanForFDCs(false)
+		}
+		return
+	}
+	if !isTrackedLog(base) {
+		return
+	}
+	switch {
+	case event.Op&fsnotify.Create != 0:
+		w.handleCreate(event.Name)
+	case event.Op&fsnotify.Write != 0:
+		w.tail(event.Name)
+	case event.Op&fsnotify.Rename != 0:
+		// The old file (e.g. AMQERR01) has just been renamed to AMQERR02/03
+		// as part of MQ's rotation. There's nothing left to read at the old
+		// path; a Create event for the replacement follows separately.
+		delete(w.offsets, event.Name)
+	}
+}
+
+// handleCreate deals with a Create event for a tracked log path. fsnotify's
+// inotify backend reports a rename-into-place (e.g. AMQERR01 rotating to
+// AMQERR02) as a Create event at the destination, as well as reporting a
+// genuinely new/truncated file the same way. Content already mirrored under
+// the old name must not be re-emitted, so a path that isn't already tracked
+// starts from its current size rather than from the beginning; a truly new
+// file is simply empty, so this still starts it at 0.
+func (w *Watcher) handleCreate(path string) {
+	if _, tracked := w.offsets[path]; !tracked {
+		if info, err := os.Stat(path); err == nil {
+			w.offsets[path] = info.Size()
+		}
+	}
+	w.tail(path)
+}
+
+func isTrackedLog(base string) bool {
+	for _, t := range trackedLogs {
+		if base == t+".LOG" || base == t+".json" {
+			return true
+		}
+	}
+	return false
+}
+
+// tail reads any bytes appended to path since it was last read, emitting one
+// Event per line.
+func (w *Watcher) tail(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+	offset := w.offsets[path]
+	if offset > info.Size() {
+		// The file was truncated or replaced since it was last read.
+		offset = 0
+	}
+	if _, err := f.Seek(offset, os.SEEK_SET); err != nil {
+		return
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		w.sink.Write(Event{
+			Time:    time.Now(),
+			Source:  path,
+			Type:    EventError,
+			Message: line,
+		})
+	}
+	if newOffset, err := f.Seek(0, os.SEEK_CUR); err == nil {
+		w.offsets[path] = newOffset
+	}
+}
+
+// scanForFDCs looks for .FDC files not yet seen. On the first call (silent),
+// pre-existing files are recorded but not reported, since they predate this
+// process and aren't a new failure.
+func (w *Watcher) scanForFDCs(silent bool) {
+	entries, err := ioutil.ReadDir(w.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".FDC") || w.seenFDC[e.Name()] {
+			continue
+		}
+		w.seenFDC[e.Name()] = true
+		if silent {
+			continue
+		}
+		path := filepath.Join(w.dir, e.Name())
+		header, err := parseFDCHeader(path)
+		if err != nil {
+			log.Printf("logmirror: reading FDC header for %v: %v", path, err)
+			continue
+		}
+		w.sink.Write(Event{
+			Time:   time.Now(),
+			Source: path,
+			Type:   EventFDC,
+			Message: fmt.Sprintf("New FDC file: %v (probe %v, component %v, severity %v)",
+				path, header.ProbeID, header.Component, header.Severity),
+			FDC: header,
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logmirror
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// recordingSink collects every Event it's given, for assertions in tests.
+type recordingSink struct {
+	events []Event
+}
+
+func (s *recordingSink) Write(e Event) error {
+	s.events = append(s.events, e)
+	return nil
+}
+
+// TestWatcherRotationDoesNotDuplicate simulates MQ renaming AMQERR01 to
+// AMQERR02 on rotation. fsnotify reports the rename destination as a Create
+// event, which must not cause the already-mirrored content to be re-emitted.
+func TestWatcherRotationDoesNotDuplicate(t *testing.T) {
+	dir := t.TempDir()
+	sink := &recordingSink{}
+	w := &Watcher{
+		dir:     dir,
+		sink:    sink,
+		offsets: map[string]int64{},
+		seenFDC: map[string]bool{},
+	}
+
+	amqerr01 := filepath.Join(dir, "AMQERR01.LOG")
+	// Start() tracks a pre-existing log from its current end; simulate it
+	// having been created empty and already tracked from offset 0.
+	if err := os.WriteFile(amqerr01, []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.offsets[amqerr01] = 0
+	if err := os.WriteFile(amqerr01, []byte("line one\nline two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.handleEvent(fsnotify.Event{Name: amqerr01, Op: fsnotify.Write})
+	if len(sink.events) != 2 {
+		t.Fatalf("after initial write: got %v events, want 2", len(sink.events))
+	}
+
+	amqerr02 := filepath.Join(dir, "AMQERR02.LOG")
+	if err := os.Rename(amqerr01, amqerr02); err != nil {
+		t.Fatal(err)
+	}
+	w.handleEvent(fsnotify.Event{Name: amqerr01, Op: fsnotify.Rename})
+	// fsnotify reports the rename destination as a Create event too.
+	w.handleEvent(fsnotify.Event{Name: amqerr02, Op: fsnotify.Create})
+	if len(sink.events) != 2 {
+		t.Fatalf("after rotation: got %v events, want 2 (no duplicates), events: %+v", len(sink.events), sink.events)
+	}
+
+	// A genuinely new AMQERR01 is created empty, then written to, each as
+	// its own fsnotify event.
+	if err := os.WriteFile(amqerr01, []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.handleEvent(fsnotify.Event{Name: amqerr01, Op: fsnotify.Create})
+	if err := os.WriteFile(amqerr01, []byte("line three\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.handleEvent(fsnotify.Event{Name: amqerr01, Op: fsnotify.Write})
+	if len(sink.events) != 3 {
+		t.Fatalf("after new AMQERR01: got %v events, want 3", len(sink.events))
+	}
+	if sink.events[2].Message != "line three" {
+		t.Errorf("events[2].Message = %q, want %q", sink.events[2].Message, "line three")
+	}
+}
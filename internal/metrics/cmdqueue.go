@@ -0,0 +1,87 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/ibm-messaging/mq-golang/ibmmq"
+
+// sendPCFCommand puts a single PCF command message built from cfh and parms
+// to the queue manager's system command queue, and collects every reply
+// message from a temporary dynamic reply queue until the PCF "last message"
+// flag is seen.
+func sendPCFCommand(qmgr ibmmq.MQQueueManager, cfh *ibmmq.MQCFH, parms []*ibmmq.PCFParameter, mqmd *ibmmq.MQMD, pmo *ibmmq.MQPMO) ([][]byte, error) {
+	cmdQ, err := qmgr.Open(commandQueueObjectDesc(), ibmmq.MQOO_OUTPUT)
+	if err != nil {
+		return nil, err
+	}
+	defer cmdQ.Close(0)
+
+	replyQ, err := qmgr.Open(replyQueueObjectDesc(), ibmmq.MQOO_INPUT_EXCLUSIVE)
+	if err != nil {
+		return nil, err
+	}
+	defer replyQ.Close(0)
+
+	mqmd.ReplyToQ = replyQ.Name
+	// The command server on SYSTEM.ADMIN.COMMAND.QUEUE ignores anything
+	// that isn't a PCF request message: without these, it silently drops
+	// the command rather than replying.
+	mqmd.Format = "MQADMIN"
+	mqmd.MsgType = ibmmq.MQMT_REQUEST
+	buf := cfh.Bytes()
+	for _, p := range parms {
+		buf = append(buf, p.Bytes()...)
+	}
+	err = cmdQ.Put(mqmd, pmo, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var replies [][]byte
+	for {
+		getmd := ibmmq.NewMQMD()
+		gmo := ibmmq.NewMQGMO()
+		gmo.Options = ibmmq.MQGMO_WAIT | ibmmq.MQGMO_CONVERT
+		gmo.WaitInterval = 3 * 1000
+		data := make([]byte, 32*1024)
+		datalen, err := replyQ.Get(getmd, gmo, data)
+		if err != nil {
+			break
+		}
+		reply := data[:datalen]
+		replies = append(replies, reply)
+		header, _ := ibmmq.ReadPCFHeader(reply)
+		if header.Control == ibmmq.MQCFC_LAST {
+			break
+		}
+	}
+	return replies, nil
+}
+
+func commandQueueObjectDesc() *ibmmq.MQOD {
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = "SYSTEM.ADMIN.COMMAND.QUEUE"
+	return mqod
+}
+
+func replyQueueObjectDesc() *ibmmq.MQOD {
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = "SYSTEM.DEFAULT.MODEL.QUEUE"
+	mqod.DynamicQName = "MQCONTAINER.METRICS.REPLY.*"
+	return mqod
+}
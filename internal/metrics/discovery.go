@@ -0,0 +1,113 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang/ibmmq"
+	log "github.com/sirupsen/logrus"
+)
+
+// queueCache holds the result of the most recent DISPLAY QLOCAL(*) PCF
+// command, so that published statistics (which only carry a queue name) can
+// be labelled with the queue's type.
+type queueCache struct {
+	mu    sync.RWMutex
+	types map[string]string
+}
+
+func newQueueCache() *queueCache {
+	return &queueCache{types: map[string]string{}}
+}
+
+// typeOf returns the cached object type for queue, or "unknown" if it
+// hasn't been discovered yet (e.g. the queue was created after the last
+// discovery pass).
+func (c *queueCache) typeOf(queue string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if t, ok := c.types[queue]; ok {
+		return t
+	}
+	return "unknown"
+}
+
+// discoverLoop periodically re-populates the cache by issuing a
+// DISPLAY QLOCAL(*) PCF command, until stop is closed.
+func (c *queueCache) discoverLoop(qmgr ibmmq.MQQueueManager, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	c.discover(qmgr)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.discover(qmgr)
+		}
+	}
+}
+
+func (c *queueCache) discover(qmgr ibmmq.MQQueueManager) {
+	names, err := displayQLocal(qmgr)
+	if err != nil {
+		log.Printf("metrics: queue discovery failed: %v", err)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		c.types[name] = "local"
+	}
+}
+
+// displayQLocal issues a DISPLAY QLOCAL(*) PCF command message to the queue
+// manager's command queue and collects the queue names from the replies.
+func displayQLocal(qmgr ibmmq.MQQueueManager) ([]string, error) {
+	cfh := ibmmq.NewMQCFH()
+	cfh.Command = ibmmq.MQCMD_INQUIRE_Q
+	buf := new(ibmmq.PCFParameter)
+	buf.Type = ibmmq.MQCFT_STRING
+	buf.Parameter = ibmmq.MQCA_Q_NAME
+	buf.String = []string{"*"}
+	// Restrict the inquiry to local queues, as DISPLAY QLOCAL(*) does.
+	qtype := new(ibmmq.PCFParameter)
+	qtype.Type = ibmmq.MQCFT_INTEGER
+	qtype.Parameter = ibmmq.MQIA_Q_TYPE
+	qtype.Int64Value = []int64{int64(ibmmq.MQQT_LOCAL)}
+
+	putmqmd := ibmmq.NewMQMD()
+	pmo := ibmmq.NewMQPMO()
+	pmo.Options = ibmmq.MQPMO_NO_SYNCPOINT | ibmmq.MQPMO_NEW_MSG_ID | ibmmq.MQPMO_NEW_CORREL_ID
+
+	replies, err := sendPCFCommand(qmgr, cfh, []*ibmmq.PCFParameter{buf, qtype}, putmqmd, pmo)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(replies))
+	for _, reply := range replies {
+		_, offset := ibmmq.ReadPCFHeader(reply)
+		for _, p := range readPCFParameters(reply[offset:]) {
+			if name, ok := pcfParameterQueueName(p); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
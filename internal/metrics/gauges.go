@@ -0,0 +1,50 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// labelNames is shared by every gauge this exporter emits, so every metric
+// can be sliced and joined consistently in Grafana/PromQL.
+var labelNames = []string{"qmgr", "queue", "type"}
+
+// gaugeSet holds the per-queue gauges published by the exporter.
+type gaugeSet struct {
+	depth *prometheus.GaugeVec
+	msgs  *prometheus.GaugeVec
+	bytes *prometheus.GaugeVec
+}
+
+func newGaugeSet() *gaugeSet {
+	return &gaugeSet{
+		depth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "ibmmq",
+			Name:      "queue_depth",
+			Help:      "Current depth of the queue",
+		}, labelNames),
+		msgs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "ibmmq",
+			Name:      "queue_msgs_total",
+			Help:      "Messages put to and got from the queue since the queue manager started",
+		}, labelNames),
+		bytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "ibmmq",
+			Name:      "queue_bytes_total",
+			Help:      "Bytes put to and got from the queue since the queue manager started",
+		}, labelNames),
+	}
+}
@@ -0,0 +1,107 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics implements an in-process Prometheus exporter for IBM MQ
+// queue manager statistics. It subscribes to the queue manager's own
+// statistics/accounting PCF publications (the same data the sibling
+// mq-metric-samples project consumes), rather than requiring a separate
+// sidecar container.
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang/ibmmq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+// discoveryInterval is how often the queue list is refreshed with a fresh
+// DISPLAY QLOCAL(*) PCF command.
+const discoveryInterval = 60 * time.Second
+
+// Exporter collects statistics for a single queue manager and serves them
+// in Prometheus text format via Handler.
+type Exporter struct {
+	qmgrName string
+	qmgr     ibmmq.MQQueueManager
+	queues   *queueCache
+	registry *prometheus.Registry
+	gauges   *gaugeSet
+}
+
+// Start connects to qmgrName, subscribes to its monitoring topics, and
+// starts background goroutines to discover queues and consume published
+// statistics until stop is closed. It returns an http.Handler suitable for
+// mounting at /metrics, e.g. as internal/httpadmin's Config.Metrics.
+func Start(qmgrName string, stop <-chan struct{}) (http.Handler, error) {
+	cno := ibmmq.NewMQCNO()
+	cno.Options = ibmmq.MQCNO_HANDLE_SHARE_BLOCK
+	qmgr, err := ibmmq.Connx(qmgrName, cno)
+	if err != nil {
+		return nil, fmt.Errorf("metrics: connecting to %v: %v", qmgrName, err)
+	}
+
+	e := &Exporter{
+		qmgrName: qmgrName,
+		qmgr:     qmgr,
+		queues:   newQueueCache(),
+		gauges:   newGaugeSet(),
+	}
+	e.registry = prometheus.NewRegistry()
+	e.registry.MustRegister(e.gauges.depth, e.gauges.msgs, e.gauges.bytes)
+
+	sub, err := subscribeMonitorTopics(qmgr, qmgrName)
+	if err != nil {
+		return nil, fmt.Errorf("metrics: subscribing to monitor topics: %v", err)
+	}
+
+	go e.queues.discoverLoop(qmgr, discoveryInterval, stop)
+	go e.consumeLoop(sub, stop)
+
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{}), nil
+}
+
+// consumeLoop reads published PCF monitoring messages and updates the
+// exporter's gauges until stop is closed.
+func (e *Exporter) consumeLoop(sub ibmmq.MQObject, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			sub.Close(0)
+			return
+		default:
+		}
+		msg, err := getMonitorMessage(sub)
+		if err != nil {
+			continue
+		}
+		samples, err := decodePCF(msg)
+		if err != nil {
+			log.Debugf("metrics: ignoring unparseable PCF message: %v", err)
+			continue
+		}
+		for _, s := range samples {
+			qtype := e.queues.typeOf(s.Queue)
+			e.gauges.depth.WithLabelValues(e.qmgrName, s.Queue, qtype).Set(float64(s.Depth))
+			e.gauges.msgs.WithLabelValues(e.qmgrName, s.Queue, qtype).Set(float64(s.Msgs))
+			e.gauges.bytes.WithLabelValues(e.qmgrName, s.Queue, qtype).Set(float64(s.Bytes))
+		}
+	}
+}
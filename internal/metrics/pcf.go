@@ -0,0 +1,199 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/ibm-messaging/mq-golang/ibmmq"
+)
+
+// monitorTopicFilter subscribes to every statistics/accounting publication
+// the queue manager makes for itself, channels and queues.
+const monitorTopicFilter = "$SYS/MQ/INFO/QMGR/%s/Monitor/#"
+
+// sample is a single queue statistic decoded from one PCF publication.
+type sample struct {
+	Queue string
+	Depth int64
+	Msgs  int64
+	Bytes int64
+}
+
+// subscribeMonitorTopics creates a non-durable subscription to qmgrName's
+// monitoring topics and returns the resulting object handle, from which
+// published messages can be retrieved with getMonitorMessage.
+func subscribeMonitorTopics(qmgr ibmmq.MQQueueManager, qmgrName string) (ibmmq.MQObject, error) {
+	mqsd := ibmmq.NewMQSD()
+	mqsd.Options = ibmmq.MQSO_CREATE | ibmmq.MQSO_NON_DURABLE | ibmmq.MQSO_FAIL_IF_QUIESCING
+	mqsd.ObjectString = fmt.Sprintf(monitorTopicFilter, qmgrName)
+	sub, err := qmgr.Sub(mqsd, nil)
+	if err != nil {
+		return ibmmq.MQObject{}, err
+	}
+	return sub, nil
+}
+
+// getMonitorMessage retrieves the next published monitoring message from
+// the managed queue behind sub, blocking for a short wait interval.
+func getMonitorMessage(sub ibmmq.MQObject) ([]byte, error) {
+	mqmd := ibmmq.NewMQMD()
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options = ibmmq.MQGMO_WAIT | ibmmq.MQGMO_CONVERT
+	gmo.WaitInterval = 3 * 1000
+	buffer := make([]byte, 32*1024)
+	datalen, err := sub.Get(mqmd, gmo, buffer)
+	if err != nil {
+		return nil, err
+	}
+	return buffer[:datalen], nil
+}
+
+// decodePCF parses a PCF statistics message and extracts the per-queue
+// samples it carries. Per-object fields are nested inside MQCFT_GROUP
+// elements (one group per queue), so the flat parameter list is first
+// regrouped before the queue name and numeric fields are read out of each
+// group together. The MQI call-count and log-utilization parameters present
+// in the same messages are decoded alongside the queue samples but are
+// intentionally left for a follow-up change, since they are qmgr-wide rather
+// than per-queue and need their own gauge shapes.
+func decodePCF(msg []byte) ([]sample, error) {
+	_, offset := ibmmq.ReadPCFHeader(msg)
+	pcfParms := groupPCFParameters(readPCFParameters(msg[offset:]))
+	samples := map[string]*sample{}
+	collectQueueSamples(pcfParms, samples)
+	out := make([]sample, 0, len(samples))
+	for _, s := range samples {
+		out = append(out, *s)
+	}
+	return out, nil
+}
+
+// collectQueueSamples walks parms looking for MQCFT_GROUP elements that
+// carry an MQCA_Q_NAME, and merges the numeric fields found in each such
+// group into samples, keyed by queue name. It recurses into every group's
+// own GroupList, since a queue's group can itself be nested inside an
+// outer one.
+func collectQueueSamples(parms []*ibmmq.PCFParameter, samples map[string]*sample) {
+	for _, p := range parms {
+		if p.Type != ibmmq.MQCFT_GROUP {
+			continue
+		}
+		if name, ok := queueNameFromGroup(p.GroupList); ok {
+			s := samples[name]
+			if s == nil {
+				s = &sample{Queue: name}
+				samples[name] = s
+			}
+			applyQueueFields(s, p.GroupList)
+		}
+		collectQueueSamples(p.GroupList, samples)
+	}
+}
+
+// applyQueueFields copies the queue depth/msgs/bytes parameters found
+// directly in group into s.
+func applyQueueFields(s *sample, group []*ibmmq.PCFParameter) {
+	for _, p := range group {
+		switch p.Parameter {
+		case ibmmq.MQIA_CURRENT_Q_DEPTH:
+			s.Depth = pcfParameterInt64(p)
+		case ibmmq.MQIAMO_MSGS:
+			s.Msgs = pcfParameterInt64(p)
+		case ibmmq.MQIAMO64_BYTES:
+			s.Bytes = pcfParameterInt64(p)
+		}
+	}
+}
+
+// readPCFParameters decodes every PCF parameter element from buf, advancing
+// by each element's own encoded length as reported by
+// ibmmq.ReadPCFParameter, until buf is exhausted. MQCFH.ParameterCount is
+// deliberately not used as a bound: it counts only top-level structures, so
+// for a message with MQCFT_GROUP elements it is smaller than the total
+// number of structures in buf (a group's members follow it in the stream
+// but aren't counted separately), which would otherwise truncate the read
+// before any group's members were reached.
+func readPCFParameters(buf []byte) []*ibmmq.PCFParameter {
+	var parms []*ibmmq.PCFParameter
+	offset := 0
+	for offset < len(buf) {
+		p, n := ibmmq.ReadPCFParameter(buf[offset:])
+		if n <= 0 {
+			break
+		}
+		parms = append(parms, p)
+		offset += n
+	}
+	return parms
+}
+
+// groupPCFParameters converts the flat sequence returned by
+// readPCFParameters into a tree: each MQCFT_GROUP element's ParameterCount
+// tells us how many of the following elements are its members, so those
+// members are consumed into its GroupList rather than left as siblings.
+// ibmmq.ReadPCFParameter itself never populates GroupList, since PCF
+// messages encode group membership by element count rather than by
+// nesting the bytes.
+func groupPCFParameters(flat []*ibmmq.PCFParameter) []*ibmmq.PCFParameter {
+	i := 0
+	var take func(n int) []*ibmmq.PCFParameter
+	take = func(n int) []*ibmmq.PCFParameter {
+		out := make([]*ibmmq.PCFParameter, 0, n)
+		for c := 0; c < n && i < len(flat); c++ {
+			p := flat[i]
+			i++
+			if p.Type == ibmmq.MQCFT_GROUP {
+				p.GroupList = take(int(p.ParameterCount))
+			}
+			out = append(out, p)
+		}
+		return out
+	}
+	return take(len(flat))
+}
+
+// pcfParameterQueueName returns the MQCA_Q_NAME string parameter's value, if
+// p carries one.
+func pcfParameterQueueName(p *ibmmq.PCFParameter) (string, bool) {
+	if p.Parameter != ibmmq.MQCA_Q_NAME {
+		return "", false
+	}
+	if len(p.String) == 0 {
+		return "", false
+	}
+	return p.String[0], true
+}
+
+// queueNameFromGroup returns the value of the first MQCA_Q_NAME parameter
+// found directly in group, if any.
+func queueNameFromGroup(group []*ibmmq.PCFParameter) (string, bool) {
+	for _, p := range group {
+		if name, ok := pcfParameterQueueName(p); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// pcfParameterInt64 reads the first integer value of a PCF parameter.
+func pcfParameterInt64(p *ibmmq.PCFParameter) int64 {
+	if len(p.Int64Value) == 0 {
+		return 0
+	}
+	return p.Int64Value[0]
+}
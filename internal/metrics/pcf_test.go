@@ -0,0 +1,74 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang/ibmmq"
+)
+
+// TestDecodePCFQueueStatistics builds a representative queue statistics PCF
+// message - a single MQCFT_GROUP carrying the queue name alongside its
+// depth, message count and byte count - and checks decodePCF descends into
+// the group instead of leaving the numeric fields at zero.
+func TestDecodePCFQueueStatistics(t *testing.T) {
+	cfh := ibmmq.NewMQCFH()
+	// A real MQCFH.ParameterCount only counts top-level structures, so it's
+	// 1 here (the group) even though 5 PCF structures follow the header.
+	cfh.ParameterCount = 1
+
+	nameParm := ibmmq.PCFParameter{Type: ibmmq.MQCFT_STRING, Parameter: ibmmq.MQCA_Q_NAME, String: []string{"DEV.QUEUE.1"}}
+	depthParm := ibmmq.PCFParameter{Type: ibmmq.MQCFT_INTEGER, Parameter: ibmmq.MQIA_CURRENT_Q_DEPTH, Int64Value: []int64{42}}
+	msgsParm := ibmmq.PCFParameter{Type: ibmmq.MQCFT_INTEGER, Parameter: ibmmq.MQIAMO_MSGS, Int64Value: []int64{7}}
+	// The real field is a 64-bit integer, but ibmmq.PCFParameter.Bytes()
+	// only serializes MQCFT_INTEGER/MQCFT_STRING, so it's encoded as a
+	// plain 32-bit integer here; decodePCF only keys off p.Parameter.
+	bytesParm := ibmmq.PCFParameter{Type: ibmmq.MQCFT_INTEGER, Parameter: ibmmq.MQIAMO64_BYTES, Int64Value: []int64{1024}}
+
+	msg := append([]byte{}, cfh.Bytes()...)
+	msg = append(msg, groupHeaderBytes(ibmmq.MQGACF_Q_STATISTICS_DATA, 4)...)
+	msg = append(msg, nameParm.Bytes()...)
+	msg = append(msg, depthParm.Bytes()...)
+	msg = append(msg, msgsParm.Bytes()...)
+	msg = append(msg, bytesParm.Bytes()...)
+
+	samples, err := decodePCF(msg)
+	if err != nil {
+		t.Fatalf("decodePCF() error = %v", err)
+	}
+	if len(samples) != 1 {
+		t.Fatalf("decodePCF() returned %d samples, want 1: %+v", len(samples), samples)
+	}
+	got := samples[0]
+	if got.Queue != "DEV.QUEUE.1" || got.Depth != 42 || got.Msgs != 7 || got.Bytes != 1024 {
+		t.Errorf("decodePCF() = %+v, want {Queue:DEV.QUEUE.1 Depth:42 Msgs:7 Bytes:1024}", got)
+	}
+}
+
+// groupHeaderBytes builds the raw bytes for an MQCFT_GROUP element with the
+// given member count, since ibmmq.PCFParameter.Bytes() doesn't serialize
+// that type.
+func groupHeaderBytes(parameter, parameterCount int32) []byte {
+	buf := make([]byte, ibmmq.MQCFGR_STRUC_LENGTH)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(ibmmq.MQCFT_GROUP))
+	binary.LittleEndian.PutUint32(buf[4:], uint32(ibmmq.MQCFGR_STRUC_LENGTH))
+	binary.LittleEndian.PutUint32(buf[8:], uint32(parameter))
+	binary.LittleEndian.PutUint32(buf[12:], uint32(parameterCount))
+	return buf
+}
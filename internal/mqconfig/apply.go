@@ -0,0 +1,51 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqconfig
+
+import (
+	"os/exec"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Apply loads the declarative configuration files in dir (if any are
+// present) and runs the rendered MQSC against the local queue manager via
+// runmqsc. It is a no-op if none of the recognised files exist.
+func Apply(dir string) error {
+	cfg, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	script := Render(cfg)
+	if script == "" {
+		return nil
+	}
+	cmd := exec.Command("runmqsc")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdin.Write([]byte(script))
+	stdin.Close()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		log.Println(err)
+	}
+	log.Printf("Output for \"runmqsc\" with declarative configuration from %v:\n\t%v", dir, strings.Replace(string(out), "\n", "\n\t", -1))
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mqconfig provides declarative configuration of queue manager
+// objects (queues, topics, channels, listeners, authentication records and
+// channel authentication rules) from YAML or JSON files, as an alternative
+// to hand-written .mqsc scripts.
+package mqconfig
+
+// Queue describes a queue to be defined on the queue manager.
+type Queue struct {
+	Name         string `yaml:"name" json:"name"`
+	Type         string `yaml:"type" json:"type"` // local, alias, remote, model
+	Description  string `yaml:"description,omitempty" json:"description,omitempty"`
+	MaxDepth     *int   `yaml:"maxDepth,omitempty" json:"maxDepth,omitempty"`
+	MaxMsgLength *int   `yaml:"maxMsgLength,omitempty" json:"maxMsgLength,omitempty"`
+	Target       string `yaml:"target,omitempty" json:"target,omitempty"` // base queue for alias/remote queues
+}
+
+// Topic describes a topic object to be defined on the queue manager.
+type Topic struct {
+	Name   string `yaml:"name" json:"name"`
+	String string `yaml:"topicString" json:"topicString"`
+}
+
+// Channel describes a channel to be defined on the queue manager.
+type Channel struct {
+	Name           string `yaml:"name" json:"name"`
+	Type           string `yaml:"type" json:"type"` // svrconn, clusrcvr, clussdr, rcvr, sdr
+	TransportType  string `yaml:"transportType,omitempty" json:"transportType,omitempty"`
+	ConnectionName string `yaml:"connectionName,omitempty" json:"connectionName,omitempty"`
+	MCAUser        string `yaml:"mcaUser,omitempty" json:"mcaUser,omitempty"`
+}
+
+// Listener describes a listener to be defined and started on the queue manager.
+type Listener struct {
+	Name string `yaml:"name" json:"name"`
+	Port int    `yaml:"port" json:"port"`
+}
+
+// AuthRecord describes an authority record, applied with SET AUTHREC.
+type AuthRecord struct {
+	Principal  string `yaml:"principal" json:"principal"`
+	ObjectType string `yaml:"objectType" json:"objectType"` // qmgr, queue, topic, ...
+	ObjectName string `yaml:"objectName,omitempty" json:"objectName,omitempty"`
+	Authority  string `yaml:"authority" json:"authority"` // setmqaut-style, e.g. "+put+get+browse"; rendered as the MQSC AUTHADD list
+}
+
+// ChannelAuthRecord describes a channel authentication rule, applied with SET CHLAUTH.
+type ChannelAuthRecord struct {
+	Channel    string `yaml:"channel" json:"channel"`
+	Type       string `yaml:"type" json:"type"` // blockuser, address, qmgrmap, ...
+	MatchValue string `yaml:"matchValue" json:"matchValue"`
+	Action     string `yaml:"action,omitempty" json:"action,omitempty"` // add, replace, remove, removeall; defaults to runmqsc's own default (ADD) if omitted or unrecognised
+	UserSource string `yaml:"userSource,omitempty" json:"userSource,omitempty"`
+}
+
+// Config is the root of a declarative configuration file. A queue manager's
+// full configuration may be assembled from several Config values loaded from
+// different files (e.g. queues.yaml, channels.yaml, auth.yaml).
+type Config struct {
+	Queues             []Queue             `yaml:"queues,omitempty" json:"queues,omitempty"`
+	Topics             []Topic             `yaml:"topics,omitempty" json:"topics,omitempty"`
+	Channels           []Channel           `yaml:"channels,omitempty" json:"channels,omitempty"`
+	Listeners          []Listener          `yaml:"listeners,omitempty" json:"listeners,omitempty"`
+	AuthRecords        []AuthRecord        `yaml:"authRecords,omitempty" json:"authRecords,omitempty"`
+	ChannelAuthRecords []ChannelAuthRecord `yaml:"channelAuthRecords,omitempty" json:"channelAuthRecords,omitempty"`
+}
+
+// Merge appends the objects from other onto c, preserving file load order so
+// that later files can extend or override earlier ones when rendered.
+func (c *Config) Merge(other Config) {
+	c.Queues = append(c.Queues, other.Queues...)
+	c.Topics = append(c.Topics, other.Topics...)
+	c.Channels = append(c.Channels, other.Channels...)
+	c.Listeners = append(c.Listeners, other.Listeners...)
+	c.AuthRecords = append(c.AuthRecords, other.AuthRecords...)
+	c.ChannelAuthRecords = append(c.ChannelAuthRecords, other.ChannelAuthRecords...)
+}
@@ -0,0 +1,80 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqconfig
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// configFiles is the set of declarative configuration files recognised under
+// /etc/mqm. Each may be supplied as either YAML or JSON.
+var configFiles = []string{"queues", "topics", "channels", "listeners", "auth", "chlauth"}
+
+// Load reads all recognised declarative configuration files (queues.yaml,
+// channels.yaml, auth.yaml, etc., or their .json equivalents) from dir and
+// merges them into a single Config. Files that do not exist are skipped.
+func Load(dir string) (*Config, error) {
+	cfg := &Config{}
+	for _, base := range configFiles {
+		path, found, err := findConfigFile(dir, base)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		var part Config
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(path, ".json") {
+			err = json.Unmarshal(data, &part)
+		} else {
+			err = yaml.Unmarshal(data, &part)
+		}
+		if err != nil {
+			return nil, err
+		}
+		cfg.Merge(part)
+	}
+	return cfg, nil
+}
+
+// findConfigFile looks for base.yaml, base.yml or base.json in dir, in that
+// order of preference, so a YAML file always wins if both are present.
+func findConfigFile(dir, base string) (path string, found bool, err error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", false, err
+	}
+	names := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		names[e.Name()] = true
+	}
+	for _, c := range []string{base + ".yaml", base + ".yml", base + ".json"} {
+		if names[c] {
+			return filepath.Join(dir, c), true, nil
+		}
+	}
+	return "", false, nil
+}
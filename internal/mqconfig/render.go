@@ -0,0 +1,186 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// quote always wraps an MQSC name or string value in single quotes, doubling
+// any single quote it contains.
+func quote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// queueType maps the short type names accepted in configuration files to the
+// MQSC object type keyword.
+func queueType(t string) string {
+	switch strings.ToLower(t) {
+	case "alias":
+		return "QALIAS"
+	case "remote":
+		return "QREMOTE"
+	case "model":
+		return "QMODEL"
+	default:
+		return "QLOCAL"
+	}
+}
+
+func channelType(t string) string {
+	switch strings.ToLower(t) {
+	case "clusrcvr":
+		return "CLUSRCVR"
+	case "clussdr":
+		return "CLUSSDR"
+	case "rcvr":
+		return "RCVR"
+	case "sdr":
+		return "SDR"
+	default:
+		return "SVRCONN"
+	}
+}
+
+// Render converts Config into an idempotent runmqsc script. Objects are
+// defined with REPLACE so that re-applying the same configuration updates
+// existing objects in place rather than failing because they already exist.
+func Render(cfg *Config) string {
+	var b strings.Builder
+	for _, q := range cfg.Queues {
+		fmt.Fprintf(&b, "DEFINE %s(%s) REPLACE", queueType(q.Type), quote(q.Name))
+		if q.MaxDepth != nil {
+			fmt.Fprintf(&b, " MAXDEPTH(%d)", *q.MaxDepth)
+		}
+		if q.MaxMsgLength != nil {
+			fmt.Fprintf(&b, " MAXMSGL(%d)", *q.MaxMsgLength)
+		}
+		if q.Target != "" {
+			fmt.Fprintf(&b, " TARGET(%s)", quote(q.Target))
+		}
+		if q.Description != "" {
+			fmt.Fprintf(&b, " DESCR(%s)", quote(q.Description))
+		}
+		b.WriteString("\n")
+	}
+	for _, t := range cfg.Topics {
+		fmt.Fprintf(&b, "DEFINE TOPIC(%s) REPLACE TOPICSTR(%s)\n", quote(t.Name), quote(t.String))
+	}
+	for _, c := range cfg.Channels {
+		fmt.Fprintf(&b, "DEFINE CHANNEL(%s) CHLTYPE(%s) REPLACE", quote(c.Name), channelType(c.Type))
+		if c.TransportType != "" {
+			fmt.Fprintf(&b, " TRPTYPE(%s)", c.TransportType)
+		}
+		if c.ConnectionName != "" {
+			fmt.Fprintf(&b, " CONNAME(%s)", quote(c.ConnectionName))
+		}
+		if c.MCAUser != "" {
+			fmt.Fprintf(&b, " MCAUSER(%s)", quote(c.MCAUser))
+		}
+		b.WriteString("\n")
+	}
+	for _, l := range cfg.Listeners {
+		fmt.Fprintf(&b, "DEFINE LISTENER(%s) TRPTYPE(TCP) PORT(%d) REPLACE\n", quote(l.Name), l.Port)
+		fmt.Fprintf(&b, "START LISTENER(%s)\n", quote(l.Name))
+	}
+	for _, a := range cfg.AuthRecords {
+		b.WriteString("SET AUTHREC")
+		// SET AUTHREC takes no PROFILE clause for OBJTYPE(QMGR); there's no
+		// object name to qualify it with.
+		if strings.ToLower(a.ObjectType) != "qmgr" {
+			fmt.Fprintf(&b, " PROFILE(%s)", quote(a.ObjectName))
+		}
+		fmt.Fprintf(&b, " OBJTYPE(%s) PRINCIPAL(%s) AUTHADD(%s)\n",
+			strings.ToUpper(a.ObjectType), quote(a.Principal), authorityList(a.Authority))
+	}
+	for _, a := range cfg.ChannelAuthRecords {
+		fmt.Fprintf(&b, "SET CHLAUTH(%s) TYPE(%s) %s(%s)",
+			quote(a.Channel), chlauthType(a.Type), strings.ToUpper(matchKeyword(a.Type)), quote(a.MatchValue))
+		if action := chlauthAction(a.Action); action != "" {
+			fmt.Fprintf(&b, " ACTION(%s)", action)
+		}
+		if a.UserSource != "" {
+			fmt.Fprintf(&b, " USERSRC(%s)", strings.ToUpper(a.UserSource))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// authorityList turns a setmqaut-style authority string, e.g.
+// "+put+get+browse", into the comma-separated keyword list MQSC's
+// SET AUTHREC AUTHADD expects, e.g. "PUT,GET,BROWSE".
+func authorityList(authority string) string {
+	var keywords []string
+	for _, tok := range strings.Split(authority, "+") {
+		if tok == "" {
+			continue
+		}
+		keywords = append(keywords, strings.ToUpper(tok))
+	}
+	return strings.Join(keywords, ",")
+}
+
+// chlauthType maps the short CHLAUTH type names accepted in configuration
+// files to the MQSC TYPE keyword.
+func chlauthType(t string) string {
+	switch strings.ToLower(t) {
+	case "blockuser":
+		return "BLOCKUSER"
+	case "blockaddr":
+		return "BLOCKADDR"
+	case "usermap":
+		return "USERMAP"
+	case "qmgrmap":
+		return "QMGRMAP"
+	case "sslpeermap":
+		return "SSLPEERMAP"
+	default:
+		return "ADDRESSMAP"
+	}
+}
+
+// chlauthAction returns the MQSC ACTION keyword for a CHLAUTH rule (ADD,
+// REPLACE, REMOVE or REMOVEALL, controlling how this rule combines with any
+// existing one), or "" if action isn't one of those, in which case the
+// ACTION clause should be omitted and left to runmqsc's own default (ADD).
+func chlauthAction(action string) string {
+	switch strings.ToUpper(action) {
+	case "ADD", "REPLACE", "REMOVE", "REMOVEALL":
+		return strings.ToUpper(action)
+	default:
+		return ""
+	}
+}
+
+// matchKeyword returns the MQSC keyword that carries the match value for a
+// given CHLAUTH rule type, e.g. ADDRESS(...) for TYPE(ADDRESSMAP).
+func matchKeyword(chlauthType string) string {
+	switch strings.ToLower(chlauthType) {
+	case "blockuser":
+		return "USERLIST"
+	case "usermap":
+		return "CLNTUSER"
+	case "qmgrmap":
+		return "QMNAME"
+	case "sslpeermap":
+		return "SSLPEER"
+	default:
+		return "ADDRESS"
+	}
+}
@@ -0,0 +1,84 @@
+/*
+© Copyright IBM Corporation 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqconfig
+
+import "testing"
+
+func TestRenderAuthRecordQMgrOmitsProfile(t *testing.T) {
+	cfg := &Config{
+		AuthRecords: []AuthRecord{
+			{Principal: "mqm", ObjectType: "qmgr", Authority: "+connect"},
+		},
+	}
+	got := Render(cfg)
+	want := "SET AUTHREC OBJTYPE(QMGR) PRINCIPAL('mqm') AUTHADD(CONNECT)\n"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderAuthRecordObjectIncludesProfile(t *testing.T) {
+	cfg := &Config{
+		AuthRecords: []AuthRecord{
+			{Principal: "app", ObjectType: "queue", ObjectName: "DEV.QUEUE.1", Authority: "+put+get"},
+		},
+	}
+	got := Render(cfg)
+	want := "SET AUTHREC PROFILE('DEV.QUEUE.1') OBJTYPE(QUEUE) PRINCIPAL('app') AUTHADD(PUT,GET)\n"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderChannelAuthRecordType(t *testing.T) {
+	cases := []struct {
+		chlauthType string
+		wantType    string
+		wantMatch   string
+	}{
+		{"address", "ADDRESSMAP", "ADDRESS"},
+		{"blockuser", "BLOCKUSER", "USERLIST"},
+		{"usermap", "USERMAP", "CLNTUSER"},
+		{"qmgrmap", "QMGRMAP", "QMNAME"},
+		{"sslpeermap", "SSLPEERMAP", "SSLPEER"},
+	}
+	for _, c := range cases {
+		cfg := &Config{
+			ChannelAuthRecords: []ChannelAuthRecord{
+				{Channel: "DEV.APP.SVRCONN", Type: c.chlauthType, MatchValue: "*", Action: "replace"},
+			},
+		}
+		got := Render(cfg)
+		want := "SET CHLAUTH('DEV.APP.SVRCONN') TYPE(" + c.wantType + ") " + c.wantMatch + "('*') ACTION(REPLACE)\n"
+		if got != want {
+			t.Errorf("Render() for type %q = %q, want %q", c.chlauthType, got, want)
+		}
+	}
+}
+
+func TestRenderChannelAuthRecordOmitsUnrecognisedAction(t *testing.T) {
+	cfg := &Config{
+		ChannelAuthRecords: []ChannelAuthRecord{
+			{Channel: "DEV.APP.SVRCONN", Type: "blockaddr", MatchValue: "9.20.42.*", Action: "blockaddr"},
+		},
+	}
+	got := Render(cfg)
+	want := "SET CHLAUTH('DEV.APP.SVRCONN') TYPE(BLOCKADDR) ADDRESS('9.20.42.*')\n"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
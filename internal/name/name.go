@@ -0,0 +1,34 @@
+/*
+© Copyright IBM Corporation 2017, 2018
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package name resolves the name of the queue manager this container should
+// create and run.
+package name
+
+import "os"
+
+// defaultName is used when MQ_QMGR_NAME isn't set.
+const defaultName = "QM1"
+
+// GetQueueManagerName returns the queue manager name to use, taken from the
+// MQ_QMGR_NAME environment variable, defaulting to "QM1" if it isn't set.
+func GetQueueManagerName() (string, error) {
+	name, ok := os.LookupEnv("MQ_QMGR_NAME")
+	if !ok || name == "" {
+		return defaultName, nil
+	}
+	return name, nil
+}